@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	latencyMin    = 100 * time.Microsecond
+	latencyMax    = 60 * time.Second
+	latencyGrowth = 1.05 // ~5% relative error per bucket
+)
+
+// latencyBounds are the upper bound of each histogram bucket, shared by
+// every histogram so buckets line up across op types.
+var latencyBounds = buildLatencyBounds(latencyMin, latencyMax, latencyGrowth)
+
+func buildLatencyBounds(min, max time.Duration, growth float64) []time.Duration {
+	var bounds []time.Duration
+	for v := float64(min); v < float64(max); v *= growth {
+		bounds = append(bounds, time.Duration(v))
+	}
+	return append(bounds, max)
+}
+
+// histogram is an exponentially-bucketed latency recorder guarded by a
+// single mutex. It is not sharded: every worker recording into the same
+// histogram takes that one lock on each completed request.
+type histogram struct {
+	mu        sync.Mutex
+	counts    []int64
+	count     int64
+	sum       time.Duration
+	min, max  time.Duration
+	samples   []time.Duration
+	recordAll bool
+}
+
+func newHistogram(recordSamples bool) *histogram {
+	return &histogram{
+		counts:    make([]int64, len(latencyBounds)),
+		recordAll: recordSamples,
+	}
+}
+
+func (h *histogram) record(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.count++
+	h.sum += d
+	idx := sort.Search(len(latencyBounds), func(i int) bool { return latencyBounds[i] >= d })
+	if idx == len(latencyBounds) {
+		idx--
+	}
+	h.counts[idx]++
+	if h.recordAll {
+		h.samples = append(h.samples, d)
+	}
+}
+
+// percentile returns the smallest bucket bound covering at least p percent
+// of recorded samples (p in (0, 100]).
+func (h *histogram) percentile(p float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == 0 {
+		return 0
+	}
+	target := int64(math.Ceil(p / 100 * float64(h.count)))
+	if target < 1 {
+		target = 1
+	}
+	var cum int64
+	for i, n := range h.counts {
+		cum += n
+		if cum >= target {
+			return latencyBounds[i]
+		}
+	}
+	return h.max
+}
+
+func (h *histogram) mean() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.count)
+}
+
+// printRow writes a single summary line for this histogram under the given
+// op label.
+func (h *histogram) printRow(op string) {
+	h.mu.Lock()
+	count, min, max := h.count, h.min, h.max
+	h.mu.Unlock()
+	if count == 0 {
+		fmt.Printf("%-6s count=0\n", op)
+		return
+	}
+	fmt.Printf("%-6s count=%d min=%v mean=%v p50=%v p90=%v p95=%v p99=%v p999=%v max=%v\n",
+		op, count, min, h.mean(),
+		h.percentile(50), h.percentile(90), h.percentile(95), h.percentile(99), h.percentile(99.9),
+		max)
+}
+
+// writeLatencyCSV dumps every raw sample recorded across hists to path, one
+// row per request, for offline analysis of a run.
+func writeLatencyCSV(path string, hists map[string]*histogram) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+	if err := w.Write([]string{"op", "duration_ns"}); err != nil {
+		return err
+	}
+	for op, h := range hists {
+		h.mu.Lock()
+		samples := append([]time.Duration(nil), h.samples...)
+		h.mu.Unlock()
+		for _, d := range samples {
+			if err := w.Write([]string{op, fmt.Sprintf("%d", d.Nanoseconds())}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
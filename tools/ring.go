@@ -0,0 +1,68 @@
+package main
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// replicasPerNode is the number of virtual nodes placed on the ring for
+// each real node, to keep the key distribution across nodes balanced.
+const replicasPerNode = 100
+
+// ring is a consistent-hash ring mapping keys to node base URLs, so a
+// client can route requests directly to the node responsible for a key
+// without going through a front-door proxy.
+type ring struct {
+	mu     sync.RWMutex
+	nodes  map[uint32]string
+	sorted []uint32
+}
+
+func newRing() *ring {
+	return &ring{nodes: make(map[uint32]string)}
+}
+
+// AddNode places replicasPerNode virtual nodes for node on the ring.
+func (r *ring) AddNode(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := 0; i < replicasPerNode; i++ {
+		h := crc32.ChecksumIEEE([]byte(node + "#" + strconv.Itoa(i)))
+		r.nodes[h] = node
+		r.sorted = append(r.sorted, h)
+	}
+	sort.Slice(r.sorted, func(i, j int) bool { return r.sorted[i] < r.sorted[j] })
+}
+
+// RemoveNode removes every virtual node belonging to node.
+func (r *ring) RemoveNode(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := make([]uint32, 0, len(r.sorted))
+	for _, h := range r.sorted {
+		if r.nodes[h] == node {
+			delete(r.nodes, h)
+			continue
+		}
+		kept = append(kept, h)
+	}
+	r.sorted = kept
+}
+
+// Get returns the node responsible for key: the first virtual node hash at
+// or after crc32(key) on the ring, wrapping around to index 0.
+func (r *ring) Get(key string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.sorted) == 0 {
+		return ""
+	}
+	h := crc32.ChecksumIEEE([]byte(key))
+	idx := sort.Search(len(r.sorted), func(i int) bool { return r.sorted[i] >= h })
+	if idx == len(r.sorted) {
+		idx = 0
+	}
+	return r.nodes[r.sorted[idx]]
+}
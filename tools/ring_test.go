@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRingGetEmpty(t *testing.T) {
+	r := newRing()
+	if got := r.Get("foo"); got != "" {
+		t.Fatalf("Get on empty ring = %q, want empty string", got)
+	}
+}
+
+func TestRingGetIsStable(t *testing.T) {
+	r := newRing()
+	r.AddNode("http://localhost:3000")
+	r.AddNode("http://localhost:3001")
+	r.AddNode("http://localhost:3002")
+
+	for _, key := range []string{"a", "benchmark-1", "some-other-key"} {
+		first := r.Get(key)
+		for i := 0; i < 10; i++ {
+			if got := r.Get(key); got != first {
+				t.Fatalf("Get(%q) = %q, want stable %q", key, got, first)
+			}
+		}
+	}
+}
+
+func TestRingGetDistributesAcrossNodes(t *testing.T) {
+	r := newRing()
+	nodes := []string{"http://localhost:3000", "http://localhost:3001", "http://localhost:3002"}
+	for _, n := range nodes {
+		r.AddNode(n)
+	}
+
+	seen := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("benchmark-%d", i)
+		seen[r.Get(key)]++
+	}
+
+	if len(seen) != len(nodes) {
+		t.Fatalf("keys landed on %d distinct nodes, want %d: %v", len(seen), len(nodes), seen)
+	}
+}
+
+func TestRingRemoveNode(t *testing.T) {
+	r := newRing()
+	r.AddNode("http://localhost:3000")
+	r.AddNode("http://localhost:3001")
+
+	key := "some-key"
+	before := r.Get(key)
+
+	r.RemoveNode("http://localhost:3000")
+	r.RemoveNode("http://localhost:3001")
+	if got := r.Get(key); got != "" {
+		t.Fatalf("Get after removing all nodes = %q, want empty string", got)
+	}
+
+	r.AddNode(before)
+	if got := r.Get(key); got != before {
+		t.Fatalf("Get after re-adding %q = %q, want %q", before, got, before)
+	}
+}
+
+func TestRingRemoveNodeKeepsOthersRoutable(t *testing.T) {
+	r := newRing()
+	r.AddNode("http://localhost:3000")
+	r.AddNode("http://localhost:3001")
+	r.AddNode("http://localhost:3002")
+
+	r.RemoveNode("http://localhost:3001")
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("benchmark-%d", i)
+		if got := r.Get(key); got == "http://localhost:3001" {
+			t.Fatalf("Get(%q) returned removed node %q", key, got)
+		}
+	}
+}
@@ -0,0 +1,660 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// httpStatusError is returned by the remote_* helpers when the server
+// responds with an unexpected status code, so retry logic can distinguish
+// a bad status (sometimes retriable) from a transport-level failure
+// (always retriable).
+type httpStatusError struct {
+	status int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("wrong status code %d", e.status)
+}
+
+// isRetriable reports whether err is worth retrying: transport-level
+// errors always are, and so are 429s and 5xx responses; anything else
+// (400, 404, 409, ...) is a permanent failure.
+func isRetriable(err error) bool {
+	var se *httpStatusError
+	if errors.As(err, &se) {
+		return se.status == http.StatusTooManyRequests || se.status >= 500
+	}
+	return err != nil
+}
+
+// copied from lib.go
+func remote_delete(remote string) error {
+	req, err := http.NewRequest("DELETE", remote, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 204 {
+		return &httpStatusError{resp.StatusCode}
+	}
+	return nil
+}
+
+// remote_put uploads body, sending digest (the base64-encoded MD5 of the
+// body, as in RFC 1864) as the Content-MD5 header so the server can reject
+// a corrupted upload on its own end.
+func remote_put(remote string, length int64, body io.Reader, digest string) error {
+	req, err := http.NewRequest("PUT", remote, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-MD5", digest)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 201 && resp.StatusCode != 204 {
+		return &httpStatusError{resp.StatusCode}
+	}
+	return nil
+}
+
+// remote_get fetches remote and returns its body along with the response
+// headers, so callers can recompute a digest over the body and compare it
+// against one the server echoed back.
+func remote_get(remote string) ([]byte, http.Header, error) {
+	resp, err := http.Get(remote)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, nil, &httpStatusError{resp.StatusCode}
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, resp.Header, nil
+}
+
+// md5Digest returns the base64-encoded MD5 of data, matching the
+// Content-MD5 format used by remote_put.
+func md5Digest(data []byte) string {
+	sum := md5.Sum(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// remote_get_status fetches remote and returns only the status code, for
+// callers that just need to assert on a response (e.g. a 404 after a
+// DELETE) without caring about the body.
+func remote_get_status(remote string) (int, error) {
+	resp, err := http.Get(remote)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// opKind is the operation a worker performs on a given iteration.
+type opKind int
+
+const (
+	opPut opKind = iota
+	opGet
+	opDel
+)
+
+// keyEntry is a key the thrasher PUT, along with the digest it recorded for
+// the value at PUT time so a later GET can detect silent corruption.
+type keyEntry struct {
+	key    string
+	digest string
+}
+
+// keyset tracks the keys the thrasher believes exist on the server, so GETs
+// and DELETEs can target keys that were actually PUT instead of guessing.
+type keyset struct {
+	mu      sync.Mutex
+	entries []keyEntry
+}
+
+// add records digest for key, overwriting any previous entry for the same
+// key so a key that's been PUT more than once never leaves a stale digest
+// behind for a later GET to wrongly flag as corrupt.
+func (k *keyset) add(key, digest string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for i, existing := range k.entries {
+		if existing.key == key {
+			k.entries[i].digest = digest
+			return
+		}
+	}
+	k.entries = append(k.entries, keyEntry{key, digest})
+}
+
+// remove deletes the entry for key, if present. add guarantees at most one
+// entry per key, so there's never more than one to remove.
+func (k *keyset) remove(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for i, existing := range k.entries {
+		if existing.key == key {
+			k.entries[i] = k.entries[len(k.entries)-1]
+			k.entries = k.entries[:len(k.entries)-1]
+			return
+		}
+	}
+}
+
+// digest returns the digest currently recorded for key, or false if key is
+// not (or no longer) present. Callers use this to tell a genuinely corrupt
+// response apart from one that's merely stale: if the recorded digest has
+// moved on since a caller snapshotted it via random(), a concurrent PUT (or
+// DELETE) raced the caller's request, not the server.
+func (k *keyset) digest(key string) (string, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, existing := range k.entries {
+		if existing.key == key {
+			return existing.digest, true
+		}
+	}
+	return "", false
+}
+
+// random returns a key entry known to exist, or false if the keyset is empty.
+func (k *keyset) random() (keyEntry, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if len(k.entries) == 0 {
+		return keyEntry{}, false
+	}
+	return k.entries[rand.Intn(len(k.entries))], true
+}
+
+// counters accumulates per-op success/failure totals across all workers.
+type counters struct {
+	putOK, putFail                int64
+	getOK, getFail                int64
+	delOK, delFail                int64
+	verifyOK, verifyFail          int64
+	corrupt                       int64
+	putAttempts, putRetries       int64
+	getAttempts, getRetries       int64
+	delAttempts, delRetries       int64
+	verifyAttempts, verifyRetries int64
+	getRaced, delRaced            int64
+
+	putLatency, getLatency, delLatency *histogram
+}
+
+func newCounters(recordSamples bool) *counters {
+	return &counters{
+		putLatency: newHistogram(recordSamples),
+		getLatency: newHistogram(recordSamples),
+		delLatency: newHistogram(recordSamples),
+	}
+}
+
+func (c *counters) print(d time.Duration) {
+	total := atomic.LoadInt64(&c.putOK) + atomic.LoadInt64(&c.putFail) +
+		atomic.LoadInt64(&c.getOK) + atomic.LoadInt64(&c.getFail) +
+		atomic.LoadInt64(&c.delOK) + atomic.LoadInt64(&c.delFail)
+	fmt.Printf("PUT    ok=%d fail=%d attempts=%d retries=%d\n", atomic.LoadInt64(&c.putOK), atomic.LoadInt64(&c.putFail), atomic.LoadInt64(&c.putAttempts), atomic.LoadInt64(&c.putRetries))
+	fmt.Printf("GET    ok=%d fail=%d attempts=%d retries=%d raced=%d\n", atomic.LoadInt64(&c.getOK), atomic.LoadInt64(&c.getFail), atomic.LoadInt64(&c.getAttempts), atomic.LoadInt64(&c.getRetries), atomic.LoadInt64(&c.getRaced))
+	fmt.Printf("DELETE ok=%d fail=%d attempts=%d retries=%d raced=%d\n", atomic.LoadInt64(&c.delOK), atomic.LoadInt64(&c.delFail), atomic.LoadInt64(&c.delAttempts), atomic.LoadInt64(&c.delRetries), atomic.LoadInt64(&c.delRaced))
+	fmt.Printf("DELETE->GET 404 verified=%d mismatched=%d attempts=%d retries=%d\n", atomic.LoadInt64(&c.verifyOK), atomic.LoadInt64(&c.verifyFail), atomic.LoadInt64(&c.verifyAttempts), atomic.LoadInt64(&c.verifyRetries))
+	fmt.Printf("Benign keyset races (GET/DELETE lost to a concurrent PUT or DELETE, not a failure): get=%d delete=%d\n", atomic.LoadInt64(&c.getRaced), atomic.LoadInt64(&c.delRaced))
+	fmt.Printf("Content integrity: corrupt=%d\n", atomic.LoadInt64(&c.corrupt))
+	fmt.Printf("Total operations: %d in %v, that's %.2f ops/sec\n", total, d, float64(total)/d.Seconds())
+	fmt.Println("Latency:")
+	c.putLatency.printRow("PUT")
+	c.getLatency.printRow("GET")
+	c.delLatency.printRow("DELETE")
+}
+
+const numWorkers = 16
+
+func randomValue(size int) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	buf := make([]byte, size)
+	for i := range buf {
+		buf[i] = alphabet[rand.Intn(len(alphabet))]
+	}
+	return string(buf)
+}
+
+// pickOp rolls the dice against the configured put/get/del percentages and
+// returns which operation a worker should perform next. parseFlags
+// validates that putsPct+getsPct+delsPct == 100, so a roll landing past
+// the del threshold only happens to floating-point rounding and is folded
+// into the del bucket.
+func pickOp(putsPct, getsPct, delsPct float64) opKind {
+	roll := rand.Float64() * 100
+	switch {
+	case roll < putsPct:
+		return opPut
+	case roll < putsPct+getsPct:
+		return opGet
+	default:
+		return opDel
+	}
+}
+
+// verifyDigest recomputes the MD5 of body and compares it against the
+// digest recorded at PUT time, and, if the server echoed one back, against
+// the response's own Content-MD5 header.
+func verifyDigest(entry keyEntry, body []byte, headers http.Header) bool {
+	got := md5Digest(body)
+	if got != entry.digest {
+		fmt.Printf("CORRUPT %s: expected digest %s, got %s\n", entry.key, entry.digest, got)
+		return false
+	}
+	if echoed := headers.Get("Content-MD5"); echoed != "" && echoed != entry.digest {
+		fmt.Printf("CORRUPT %s: server echoed digest %s, expected %s\n", entry.key, echoed, entry.digest)
+		return false
+	}
+	return true
+}
+
+// options holds the flag-parsed configuration for a thrasher run.
+type options struct {
+	ops            int
+	putsPct        float64
+	getsPct        float64
+	delsPct        float64
+	keyspace       int
+	valueSize      int
+	duration       time.Duration
+	verify         bool
+	putRPS         int
+	getRPS         int
+	putConcurrency int
+	getConcurrency int
+	maxRetries     int
+	baseBackoff    time.Duration
+	latencyCSV     string
+	nodes          string
+}
+
+// withRetry calls fn, retrying on retriable errors with exponential backoff
+// plus jitter (base * 2^attempt + uniform[0, base)) up to maxRetries times.
+// attempts and retries are bumped on every call and every retry
+// respectively; the caller is still responsible for bumping its own
+// failure counter when withRetry returns a non-nil error.
+func withRetry(maxRetries int, baseBackoff time.Duration, attempts, retries *int64, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		atomic.AddInt64(attempts, 1)
+		err = fn()
+		if err == nil || !isRetriable(err) || attempt >= maxRetries {
+			return err
+		}
+		atomic.AddInt64(retries, 1)
+		backoff := baseBackoff * time.Duration(int64(1)<<uint(attempt))
+		if baseBackoff > 0 {
+			backoff += time.Duration(rand.Int63n(int64(baseBackoff)))
+		}
+		time.Sleep(backoff)
+	}
+}
+
+// tokenBucket paces callers to at most rps operations per second: a ticker
+// refills a buffered channel of capacity rps, and take blocks until a token
+// is available. A nil *tokenBucket is treated as unlimited.
+type tokenBucket struct {
+	tokens chan struct{}
+}
+
+func newTokenBucket(rps int) *tokenBucket {
+	if rps <= 0 {
+		return nil
+	}
+	tb := &tokenBucket{tokens: make(chan struct{}, rps)}
+	for i := 0; i < rps; i++ {
+		tb.tokens <- struct{}{}
+	}
+	go func() {
+		ticker := time.NewTicker(time.Second / time.Duration(rps))
+		defer ticker.Stop()
+		for range ticker.C {
+			select {
+			case tb.tokens <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return tb
+}
+
+func (tb *tokenBucket) take() {
+	if tb == nil {
+		return
+	}
+	<-tb.tokens
+}
+
+// semaphore bounds the number of in-flight requests independently of the
+// rate limit above. A nil semaphore is treated as unbounded.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+func (s semaphore) acquire() {
+	if s != nil {
+		s <- struct{}{}
+	}
+}
+
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
+
+// limiters holds the per-endpoint rate limit and concurrency bound applied
+// before a worker is allowed to issue a PUT or GET.
+type limiters struct {
+	putRate, getRate *tokenBucket
+	putSem, getSem   semaphore
+}
+
+// remoteURL returns the full URL for key, routed to whichever node the ring
+// assigns it to.
+func remoteURL(r *ring, key string) string {
+	return r.Get(key) + "/" + key
+}
+
+// doPut issues one PUT of a freshly generated key/value pair and records it
+// in keys on success. It always performs a real request, so callers can
+// treat it as a safe fallback whenever there's nothing to GET or DELETE
+// yet.
+func doPut(r *ring, keys *keyset, c *counters, opts options, lim *limiters) {
+	key := fmt.Sprintf("benchmark-%d", rand.Intn(opts.keyspace))
+	value := randomValue(opts.valueSize)
+	digest := md5Digest([]byte(value))
+
+	opStart := time.Now()
+	err := withRetry(opts.maxRetries, opts.baseBackoff, &c.putAttempts, &c.putRetries, func() error {
+		lim.putRate.take()
+		lim.putSem.acquire()
+		defer lim.putSem.release()
+		return remote_put(remoteURL(r, key), int64(len(value)), strings.NewReader(value), digest)
+	})
+	c.putLatency.record(time.Since(opStart))
+	if err != nil {
+		fmt.Println("PUT FAILED", err)
+		atomic.AddInt64(&c.putFail, 1)
+		return
+	}
+	keys.add(key, digest)
+	atomic.AddInt64(&c.putOK, 1)
+}
+
+// doGet issues one GET against a key known to exist. If the keyset is
+// currently empty it falls back to doPut, so every call performs a real
+// request instead of a silent no-op.
+func doGet(r *ring, keys *keyset, c *counters, opts options, lim *limiters) {
+	entry, ok := keys.random()
+	if !ok {
+		doPut(r, keys, c, opts, lim)
+		return
+	}
+	var body []byte
+	var headers http.Header
+	opStart := time.Now()
+	err := withRetry(opts.maxRetries, opts.baseBackoff, &c.getAttempts, &c.getRetries, func() error {
+		lim.getRate.take()
+		lim.getSem.acquire()
+		defer lim.getSem.release()
+		b, h, err := remote_get(remoteURL(r, entry.key))
+		if err != nil {
+			return err
+		}
+		body, headers = b, h
+		return nil
+	})
+	c.getLatency.record(time.Since(opStart))
+	if err != nil {
+		var se *httpStatusError
+		if errors.As(err, &se) && se.status == http.StatusNotFound {
+			// entry.key may have been removed by a concurrent DELETE between
+			// keys.random() picking it and this GET reaching the server;
+			// that's a benign race, not a correctness failure, so it's
+			// tracked separately instead of polluting getFail.
+			atomic.AddInt64(&c.getRaced, 1)
+			return
+		}
+		fmt.Println("GET FAILED", err)
+		atomic.AddInt64(&c.getFail, 1)
+		return
+	}
+	if opts.verify {
+		current, ok := keys.digest(entry.key)
+		if !ok || current != entry.digest {
+			// entry.key was overwritten or removed by a concurrent PUT/DELETE
+			// between keys.random() snapshotting it and this GET reaching the
+			// server; the body we got back is for whichever write actually
+			// landed, so comparing it against our stale snapshot would be a
+			// false positive, not a correctness failure.
+			atomic.AddInt64(&c.getRaced, 1)
+			return
+		}
+		if !verifyDigest(entry, body, headers) {
+			atomic.AddInt64(&c.corrupt, 1)
+			atomic.AddInt64(&c.getFail, 1)
+			return
+		}
+	}
+	atomic.AddInt64(&c.getOK, 1)
+}
+
+// doDel issues one DELETE against a key known to exist, then verifies the
+// key 404s on a follow-up GET. If the keyset is currently empty it falls
+// back to doPut, so every call performs a real request instead of a
+// silent no-op.
+func doDel(r *ring, keys *keyset, c *counters, opts options, lim *limiters) {
+	entry, ok := keys.random()
+	if !ok {
+		doPut(r, keys, c, opts, lim)
+		return
+	}
+	opStart := time.Now()
+	err := withRetry(opts.maxRetries, opts.baseBackoff, &c.delAttempts, &c.delRetries, func() error {
+		return remote_delete(remoteURL(r, entry.key))
+	})
+	c.delLatency.record(time.Since(opStart))
+	if err != nil {
+		var se *httpStatusError
+		if errors.As(err, &se) && se.status == http.StatusNotFound {
+			// entry.key may already have been deleted by a concurrent
+			// DELETE that also picked it via keys.random(); that's a
+			// benign race, not a correctness failure.
+			atomic.AddInt64(&c.delRaced, 1)
+			keys.remove(entry.key)
+			return
+		}
+		fmt.Println("DELETE FAILED", err)
+		atomic.AddInt64(&c.delFail, 1)
+		return
+	}
+	keys.remove(entry.key)
+	atomic.AddInt64(&c.delOK, 1)
+
+	var status int
+	err = withRetry(opts.maxRetries, opts.baseBackoff, &c.verifyAttempts, &c.verifyRetries, func() error {
+		lim.getRate.take()
+		lim.getSem.acquire()
+		defer lim.getSem.release()
+		s, err := remote_get_status(remoteURL(r, entry.key))
+		if err != nil {
+			return err
+		}
+		status = s
+		if status != 404 {
+			return &httpStatusError{status}
+		}
+		return nil
+	})
+	if err != nil {
+		if status != 0 && status != 404 {
+			if _, ok := keys.digest(entry.key); ok {
+				// a concurrent PUT resurrected entry.key after our DELETE and
+				// before this follow-up GET caught up; the server did nothing
+				// wrong, so this isn't a verification failure.
+				atomic.AddInt64(&c.delRaced, 1)
+				return
+			}
+		}
+		fmt.Printf("DELETE->GET verification FAILED for %s: status=%d err=%v\n", entry.key, status, err)
+		atomic.AddInt64(&c.verifyFail, 1)
+		return
+	}
+	atomic.AddInt64(&c.verifyOK, 1)
+}
+
+func worker(r *ring, keys *keyset, c *counters, opts options, lim *limiters, deadline time.Time, opsRemaining *int64) {
+	for {
+		if opts.duration > 0 {
+			if time.Now().After(deadline) {
+				return
+			}
+		} else if atomic.AddInt64(opsRemaining, -1) < 0 {
+			return
+		}
+
+		switch pickOp(opts.putsPct, opts.getsPct, opts.delsPct) {
+		case opPut:
+			doPut(r, keys, c, opts, lim)
+		case opGet:
+			doGet(r, keys, c, opts, lim)
+		case opDel:
+			doDel(r, keys, c, opts, lim)
+		}
+	}
+}
+
+func parseFlags() options {
+	var opts options
+	flag.IntVar(&opts.ops, "ops", 100_000, "total number of operations to perform across all workers (ignored if --duration is set)")
+	flag.Float64Var(&opts.putsPct, "puts-pct", 34, "percentage of operations that are PUTs")
+	flag.Float64Var(&opts.getsPct, "gets-pct", 33, "percentage of operations that are GETs")
+	flag.Float64Var(&opts.delsPct, "dels-pct", 33, "percentage of operations that are DELETEs")
+	flag.IntVar(&opts.keyspace, "keyspace", 10_000, "number of distinct keys PUTs are drawn from")
+	flag.IntVar(&opts.valueSize, "value-size", 100, "size in bytes of generated PUT values")
+	flag.DurationVar(&opts.duration, "duration", 0, "run for this long instead of a fixed op count (e.g. 30s, 5m); 0 means use --ops")
+	flag.BoolVar(&opts.verify, "verify", true, "recompute and check the MD5 of every GET against the digest recorded at PUT time; disable for raw throughput runs")
+	flag.IntVar(&opts.putRPS, "put-rps", 0, "cap PUTs to this many requests per second across all workers; 0 means unlimited")
+	flag.IntVar(&opts.getRPS, "get-rps", 0, "cap GETs to this many requests per second across all workers; 0 means unlimited")
+	flag.IntVar(&opts.putConcurrency, "put-concurrency", 0, "cap the number of in-flight PUTs; 0 means unbounded")
+	flag.IntVar(&opts.getConcurrency, "get-concurrency", 0, "cap the number of in-flight GETs; 0 means unbounded")
+	flag.IntVar(&opts.maxRetries, "max-retries", 3, "retry a PUT/GET/DELETE this many times on connection errors, 429s, and 5xxs")
+	flag.DurationVar(&opts.baseBackoff, "base-backoff", 50*time.Millisecond, "base exponential backoff delay between retries (jitter of up to this much is added)")
+	flag.StringVar(&opts.latencyCSV, "latency-csv", "", "dump every recorded request latency as raw samples to this CSV path")
+	flag.StringVar(&opts.nodes, "nodes", "http://localhost:3000", "comma-separated base URLs of the minikeyvalue nodes to drive directly, keyed by a consistent-hash ring")
+	flag.Parse()
+
+	if sum := opts.putsPct + opts.getsPct + opts.delsPct; math.Abs(sum-100) > 1e-9 {
+		fmt.Fprintf(os.Stderr, "puts-pct + gets-pct + dels-pct must sum to 100, got %v\n", sum)
+		os.Exit(1)
+	}
+	if opts.keyspace <= 0 {
+		fmt.Fprintf(os.Stderr, "keyspace must be greater than 0, got %d\n", opts.keyspace)
+		os.Exit(1)
+	}
+	if opts.valueSize < 0 {
+		fmt.Fprintf(os.Stderr, "value-size must be non-negative, got %d\n", opts.valueSize)
+		os.Exit(1)
+	}
+	if !hasUsableNode(opts.nodes) {
+		fmt.Fprintf(os.Stderr, "nodes must contain at least one non-empty, comma-separated base URL, got %q\n", opts.nodes)
+		os.Exit(1)
+	}
+	return opts
+}
+
+// hasUsableNode reports whether nodes contains at least one non-blank entry
+// once split on commas and trimmed, the same parsing main uses to build the
+// ring.
+func hasUsableNode(nodes string) bool {
+	for _, node := range strings.Split(nodes, ",") {
+		if strings.TrimSpace(node) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func main() {
+	rand.Seed(time.Now().UTC().UnixNano())
+	opts := parseFlags()
+
+	http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost = 100
+
+	r := newRing()
+	for _, node := range strings.Split(opts.nodes, ",") {
+		if node = strings.TrimSpace(node); node != "" {
+			r.AddNode(node)
+		}
+	}
+	keys := &keyset{}
+	c := newCounters(opts.latencyCSV != "")
+	lim := &limiters{
+		putRate: newTokenBucket(opts.putRPS),
+		getRate: newTokenBucket(opts.getRPS),
+		putSem:  newSemaphore(opts.putConcurrency),
+		getSem:  newSemaphore(opts.getConcurrency),
+	}
+
+	fmt.Println("starting thrasher")
+	start := time.Now()
+	deadline := start.Add(opts.duration)
+
+	opsRemaining := int64(opts.ops)
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker(r, keys, c, opts, lim, deadline, &opsRemaining)
+		}()
+	}
+	wg.Wait()
+
+	c.print(time.Since(start))
+
+	if opts.latencyCSV != "" {
+		hists := map[string]*histogram{"PUT": c.putLatency, "GET": c.getLatency, "DELETE": c.delLatency}
+		if err := writeLatencyCSV(opts.latencyCSV, hists); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to write latency csv:", err)
+		}
+	}
+}